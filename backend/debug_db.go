@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
 	"os"
 	"time"
 
+	"github.com/jahanb/monitoring-system/backend/internal/app"
+	"github.com/jahanb/monitoring-system/backend/mongodbx"
+	"github.com/jahanb/monitoring-system/backend/monitors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "log the schema changes EnsureSchema would make without applying them")
+	flag.Parse()
+
 	uri := os.Getenv("MONGODB_URI")
 	if uri == "" {
 		uri = "mongodb://localhost:27017"
@@ -21,49 +26,80 @@ func main() {
 	if dbName == "" {
 		dbName = "monitoring_system"
 	}
+	writeConcern := os.Getenv("MONGODB_WRITE_CONCERN")
+	readPreference := os.Getenv("MONGODB_READ_PREFERENCE")
 
-	fmt.Printf("Connecting to MongoDB: %s (DB: %s)\n", uri, dbName)
+	a := app.New(app.Config{
+		Name:            "debug_db",
+		ShutdownTimeout: 20 * time.Second,
+	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	var client *mongo.Client
+	var db *mongo.Database
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer client.Disconnect(ctx)
+	a.OnConnect(func(ctx context.Context, a *app.App) error {
+		connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
 
-	// List Databases
-	dbs, err := client.ListDatabaseNames(ctx, bson.M{})
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Println("Databases:", dbs)
+		c, err := mongodbx.Connect(connectCtx, mongodbx.Config{
+			URI:            uri,
+			WriteConcern:   writeConcern,
+			ReadPreference: readPreference,
+			Logger:         a.Logger,
+		})
+		if err != nil {
+			return fmt.Errorf("connect: %w", err)
+		}
+		client = c
+		db = c.Database(dbName)
 
-	// List Collections
-	db := client.Database(dbName)
-	cols, err := db.ListCollectionNames(ctx, bson.M{})
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Println("Collections in", dbName, ":", cols)
+		a.Register(app.Registration{
+			Name: "mongo-client",
+			UnRegister: func(ctx context.Context) error {
+				return client.Disconnect(ctx)
+			},
+		})
+		return nil
+	})
 
-	// Dump Monitors
-	fmt.Println("\n--- Monitors ---")
-	cursor, err := db.Collection("monitors").Find(ctx, bson.M{})
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer cursor.Close(ctx)
+	a.OnAfterConnect(func(ctx context.Context, a *app.App) error {
+		a.Logger.Printf("connected to MongoDB: %s (DB: %s)", uri, dbName)
+		return nil
+	})
 
-	var results []bson.M
-	if err = cursor.All(ctx, &results); err != nil {
-		log.Fatal(err)
-	}
+	a.OnSetupIndexes(func(ctx context.Context, a *app.App) error {
+		return monitors.EnsureSchema(ctx, db, monitors.EnsureSchemaOptions{
+			DryRun: *dryRun,
+			Logger: a.Logger,
+		})
+	})
+
+	a.OnRun(func(ctx context.Context, a *app.App) error {
+		dbs, err := client.ListDatabaseNames(ctx, bson.M{})
+		if err != nil {
+			return fmt.Errorf("list databases: %w", err)
+		}
+		fmt.Println("Databases:", dbs)
+
+		cols, err := db.ListCollectionNames(ctx, bson.M{})
+		if err != nil {
+			return fmt.Errorf("list collections: %w", err)
+		}
+		fmt.Println("Collections in", dbName, ":", cols)
+
+		fmt.Println("\n--- Monitors ---")
+		repo := monitors.NewRepository(db)
+		err = repo.ForEach(ctx, nil, func(m monitors.Monitor) error {
+			fmt.Printf("ID: %s, Name: %v\n", m.ID.Hex(), m.Name)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("dump monitors: %w", err)
+		}
+		return nil
+	})
 
-	for _, result := range results {
-		id := result["_id"]
-		name := result["monitor_name"]
-		fmt.Printf("ID: %v (Type: %T), Name: %v\n", id, id, name)
+	if err := a.Run(context.Background()); err != nil {
+		a.Logger.Fatal(err)
 	}
 }
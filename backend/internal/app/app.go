@@ -0,0 +1,193 @@
+// Package app provides a small lifecycle-driven bootstrapper for the
+// monitoring-system binaries. It runs a fixed sequence of phases
+// (BeforeConnect -> Connect -> AfterConnect -> SetupLogger -> SetupIndexes ->
+// SetupRoutes -> Run) and guarantees that cleanup runs in reverse order on
+// shutdown, even when a phase fails partway through.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Phase is a single step of App's startup sequence. It receives the app so
+// it can stash whatever it sets up (a client, a logger, a router, ...) back
+// onto it for later phases to use.
+type Phase func(ctx context.Context, a *App) error
+
+// Registration is a named unit of work with an optional teardown step. Every
+// Phase that successfully registers a Registration gets its UnRegister
+// called during shutdown, in the reverse order it was added.
+type Registration struct {
+	Name       string
+	UnRegister func(ctx context.Context) error
+}
+
+// Config controls the bootstrapper's behavior.
+type Config struct {
+	// Name identifies the binary in log output.
+	Name string
+	// ShutdownTimeout bounds how long UnRegister and in-flight work are
+	// given to drain once a shutdown signal arrives.
+	ShutdownTimeout time.Duration
+}
+
+// App runs an ordered set of lifecycle phases and keeps track of the
+// registrations each phase makes so they can be torn down in reverse on
+// shutdown.
+type App struct {
+	cfg Config
+
+	Logger *log.Logger
+
+	registrations []Registration
+
+	// shutdownDeadline is set once, when a shutdown signal arrives, to
+	// time.Now().Add(ShutdownTimeout). unregisterAll uses it instead of a
+	// fresh ShutdownTimeout so the grace period for draining Run phases
+	// and the grace period for teardown share a single budget.
+	shutdownDeadline time.Time
+
+	beforeConnect []Phase
+	connect       []Phase
+	afterConnect  []Phase
+	setupLogger   []Phase
+	setupIndexes  []Phase
+	setupRoutes   []Phase
+	run           []Phase
+}
+
+// New creates an App with default logging until SetupLogger phases replace
+// it.
+func New(cfg Config) *App {
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = 15 * time.Second
+	}
+	return &App{
+		cfg:    cfg,
+		Logger: log.New(os.Stderr, fmt.Sprintf("[%s] ", cfg.Name), log.LstdFlags),
+	}
+}
+
+// OnBeforeConnect, OnConnect, OnAfterConnect, OnSetupLogger, OnSetupIndexes,
+// OnSetupRoutes and OnRun append a Phase to the corresponding stage of the
+// startup sequence. Phases within a stage run in the order they were added.
+func (a *App) OnBeforeConnect(p Phase) { a.beforeConnect = append(a.beforeConnect, p) }
+func (a *App) OnConnect(p Phase)       { a.connect = append(a.connect, p) }
+func (a *App) OnAfterConnect(p Phase)  { a.afterConnect = append(a.afterConnect, p) }
+func (a *App) OnSetupLogger(p Phase)   { a.setupLogger = append(a.setupLogger, p) }
+func (a *App) OnSetupIndexes(p Phase)  { a.setupIndexes = append(a.setupIndexes, p) }
+func (a *App) OnSetupRoutes(p Phase)   { a.setupRoutes = append(a.setupRoutes, p) }
+func (a *App) OnRun(p Phase)           { a.run = append(a.run, p) }
+
+// Register records r so its UnRegister is called during shutdown. Phases
+// call this as they acquire resources (a connected client, a background
+// goroutine, ...) that need explicit teardown.
+func (a *App) Register(r Registration) {
+	a.registrations = append(a.registrations, r)
+}
+
+// Run executes every phase in order, installs a SIGINT/SIGTERM handler that
+// cancels the context passed to Run phases, and unwinds all registrations in
+// reverse once the root context is cancelled or a phase returns an error.
+//
+// Run phases are expected to block until ctx is done; Run returns once all
+// of them have returned or, after a shutdown signal, once ShutdownTimeout
+// elapses with some still running, in which case it returns a timeout error.
+func (a *App) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	defer a.unregisterAll()
+
+	stages := []struct {
+		name   string
+		phases []Phase
+	}{
+		{"BeforeConnect", a.beforeConnect},
+		{"Connect", a.connect},
+		{"AfterConnect", a.afterConnect},
+		{"SetupLogger", a.setupLogger},
+		{"SetupIndexes", a.setupIndexes},
+		{"SetupRoutes", a.setupRoutes},
+	}
+	for _, stage := range stages {
+		for _, phase := range stage.phases {
+			if err := phase(ctx, a); err != nil {
+				return fmt.Errorf("%s: %w", stage.name, err)
+			}
+		}
+	}
+
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	errCh := make(chan error, len(a.run))
+	for _, phase := range a.run {
+		phase := phase
+		go func() {
+			errCh <- phase(runCtx, a)
+		}()
+	}
+
+	var runErr error
+	remaining := len(a.run)
+	doneCh := ctx.Done()
+	var timeoutCh <-chan time.Time
+
+	for remaining > 0 {
+		select {
+		case err := <-errCh:
+			remaining--
+			if err != nil && runErr == nil {
+				runErr = err
+				cancelRun()
+			}
+		case <-doneCh:
+			doneCh = nil
+			a.shutdownDeadline = time.Now().Add(a.cfg.ShutdownTimeout)
+			a.Logger.Printf("shutdown signal received, draining for up to %s", a.cfg.ShutdownTimeout)
+			timeoutCh = time.After(a.cfg.ShutdownTimeout)
+		case <-timeoutCh:
+			a.Logger.Printf("shutdown grace period elapsed with %d run phase(s) still active; proceeding to teardown", remaining)
+			if runErr == nil {
+				runErr = fmt.Errorf("shutdown timed out after %s waiting for run phases", a.cfg.ShutdownTimeout)
+			}
+			return runErr
+		}
+	}
+
+	return runErr
+}
+
+// unregisterAll calls UnRegister on every registration in reverse order.
+// If a shutdown signal already consumed part of ShutdownTimeout draining Run
+// phases, the remaining budget up to shutdownDeadline is what's left for
+// teardown; otherwise (no signal, e.g. a phase returned an error) it gets a
+// fresh ShutdownTimeout. Errors are logged rather than returned so one
+// failed teardown doesn't prevent the rest from running.
+func (a *App) unregisterAll() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if a.shutdownDeadline.IsZero() {
+		ctx, cancel = context.WithTimeout(context.Background(), a.cfg.ShutdownTimeout)
+	} else {
+		ctx, cancel = context.WithDeadline(context.Background(), a.shutdownDeadline)
+	}
+	defer cancel()
+
+	for i := len(a.registrations) - 1; i >= 0; i-- {
+		r := a.registrations[i]
+		if r.UnRegister == nil {
+			continue
+		}
+		if err := r.UnRegister(ctx); err != nil {
+			a.Logger.Printf("unregister %s: %v", r.Name, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestApp(shutdownTimeout time.Duration) *App {
+	return New(Config{Name: "test", ShutdownTimeout: shutdownTimeout})
+}
+
+func TestRun_PropagatesStageError(t *testing.T) {
+	a := newTestApp(time.Second)
+
+	var torndown bool
+	a.OnBeforeConnect(func(ctx context.Context, a *App) error {
+		a.Register(Registration{
+			Name:       "res",
+			UnRegister: func(ctx context.Context) error { torndown = true; return nil },
+		})
+		return nil
+	})
+	a.OnSetupIndexes(func(ctx context.Context, a *App) error {
+		return errors.New("idx failed")
+	})
+
+	var ranRun bool
+	a.OnRun(func(ctx context.Context, a *App) error {
+		ranRun = true
+		return nil
+	})
+
+	err := a.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "SetupIndexes: idx failed") {
+		t.Fatalf("Run() error = %v, want it to wrap the SetupIndexes failure", err)
+	}
+	if ranRun {
+		t.Fatal("Run phases should not start once an earlier stage fails")
+	}
+	if !torndown {
+		t.Fatal("registrations made before the failing stage should still be torn down")
+	}
+}
+
+func TestRun_RunPhaseErrorCancelsOthers(t *testing.T) {
+	a := newTestApp(time.Second)
+
+	a.OnRun(func(ctx context.Context, a *App) error {
+		return errors.New("boom")
+	})
+	a.OnRun(func(ctx context.Context, a *App) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := a.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("Run() error = %v, want the first Run phase's error", err)
+	}
+}
+
+func TestRun_ShutdownTimesOutStuckRunPhase(t *testing.T) {
+	a := newTestApp(30 * time.Millisecond)
+
+	block := make(chan struct{})
+	a.OnRun(func(ctx context.Context, a *App) error {
+		<-block
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := a.Run(ctx)
+	elapsed := time.Since(start)
+	close(block)
+
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Run() error = %v, want a shutdown-timeout error", err)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Fatalf("Run() took %s, want it bounded by ShutdownTimeout", elapsed)
+	}
+}
+
+func TestRun_TeardownSharesShutdownBudgetWithDrain(t *testing.T) {
+	a := newTestApp(50 * time.Millisecond)
+
+	a.OnRun(func(ctx context.Context, a *App) error {
+		<-ctx.Done()
+		time.Sleep(40 * time.Millisecond)
+		return nil
+	})
+
+	var remaining time.Duration
+	a.OnBeforeConnect(func(ctx context.Context, a *App) error {
+		a.Register(Registration{
+			Name: "res",
+			UnRegister: func(ctx context.Context) error {
+				if dl, ok := ctx.Deadline(); ok {
+					remaining = time.Until(dl)
+				}
+				return nil
+			},
+		})
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+
+	if err := a.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// The Run phase already spent ~40ms of the 50ms ShutdownTimeout
+	// draining, so teardown should inherit what's left of that single
+	// budget, not a fresh 50ms.
+	if remaining > 20*time.Millisecond {
+		t.Fatalf("unregisterAll got %s remaining, want it bounded by the shared shutdown budget", remaining)
+	}
+}
+
+func TestRun_TeardownRunsInReverseOrder(t *testing.T) {
+	a := newTestApp(time.Second)
+
+	var order []string
+	a.OnBeforeConnect(func(ctx context.Context, a *App) error {
+		a.Register(Registration{Name: "a", UnRegister: func(ctx context.Context) error {
+			order = append(order, "a")
+			return nil
+		}})
+		return nil
+	})
+	a.OnConnect(func(ctx context.Context, a *App) error {
+		a.Register(Registration{Name: "b", UnRegister: func(ctx context.Context) error {
+			order = append(order, "b")
+			return nil
+		}})
+		a.Register(Registration{Name: "c", UnRegister: func(ctx context.Context) error {
+			order = append(order, "c")
+			return nil
+		}})
+		return nil
+	})
+
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"c", "b", "a"}
+	if len(order) != len(want) {
+		t.Fatalf("teardown order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("teardown order = %v, want %v", order, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package mongodbx wraps mongo.Connect with this module's durability
+// defaults: an explicit write concern and read preference derived from
+// Config, with a standalone-topology safeguard so a misconfigured
+// w:majority doesn't hang forever.
+package mongodbx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// connectLogger is the subset of *log.Logger Connect needs.
+type connectLogger interface {
+	Printf(format string, v ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(string, ...interface{}) {}
+
+// Config describes how to connect to MongoDB and with what durability
+// guarantees.
+type Config struct {
+	URI string
+
+	// WriteConcern is "majority", "0" (unacknowledged), or a positive
+	// integer string naming the number of nodes to acknowledge. Defaults
+	// to "majority".
+	WriteConcern string
+	// WriteConcernJournal requires the write to be committed to the
+	// on-disk journal.
+	WriteConcernJournal bool
+	// WriteConcernWTimeout bounds how long the server waits for the
+	// write concern to be satisfied.
+	WriteConcernWTimeout time.Duration
+
+	// ReadPreference is "primary", "primaryPreferred", "secondary",
+	// "secondaryPreferred" or "nearest". Defaults to "primary".
+	ReadPreference string
+
+	// Logger receives one line describing the effective write concern
+	// and read preference once connected. Defaults to a no-op logger.
+	Logger connectLogger
+}
+
+// Connect dials MongoDB per cfg, downgrading a requested w:majority to w:1
+// if the server turns out to be a standalone, since a standalone can never
+// satisfy majority write concern and the write would hang until it times
+// out.
+func Connect(ctx context.Context, cfg Config) (*mongo.Client, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI))
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	standalone, err := isStandalone(ctx, client)
+	if err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("probe topology: %w", err)
+	}
+
+	wc, err := buildWriteConcern(cfg, standalone)
+	if err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+	rp, err := buildReadPreference(cfg)
+	if err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+
+	_ = client.Disconnect(ctx)
+
+	client, err = mongo.Connect(ctx, options.Client().
+		ApplyURI(cfg.URI).
+		SetWriteConcern(wc).
+		SetReadPreference(rp))
+	if err != nil {
+		return nil, fmt.Errorf("connect with durability options: %w", err)
+	}
+
+	logger.Printf("mongodbx: connected with write concern %s, read preference %s (standalone=%t)",
+		describeWriteConcern(cfg, standalone), cfg.readPreferenceOrDefault(), standalone)
+
+	return client, nil
+}
+
+// isStandalone probes the server with hello (isMaster on older servers) to
+// determine whether it's running as a standalone node rather than part of a
+// replica set.
+func isStandalone(ctx context.Context, client *mongo.Client) (bool, error) {
+	var reply struct {
+		SetName string `bson:"setName"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&reply)
+	if err != nil {
+		err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&reply)
+	}
+	if err != nil {
+		return false, err
+	}
+	return reply.SetName == "", nil
+}
+
+func buildWriteConcern(cfg Config, standalone bool) (*writeconcern.WriteConcern, error) {
+	w := cfg.WriteConcern
+	if w == "" {
+		w = "majority"
+	}
+	if w == "majority" && standalone {
+		w = "1"
+	}
+
+	opts := []writeconcern.Option{}
+	if cfg.WriteConcernJournal {
+		opts = append(opts, writeconcern.J(true))
+	}
+	if cfg.WriteConcernWTimeout > 0 {
+		opts = append(opts, writeconcern.WTimeout(cfg.WriteConcernWTimeout))
+	}
+
+	switch w {
+	case "majority":
+		opts = append(opts, writeconcern.WMajority())
+	case "0":
+		opts = append(opts, writeconcern.W(0))
+	default:
+		n, err := strconv.Atoi(w)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid write concern %q", w)
+		}
+		opts = append(opts, writeconcern.W(n))
+	}
+
+	return writeconcern.New(opts...), nil
+}
+
+func buildReadPreference(cfg Config) (*readpref.ReadPref, error) {
+	switch cfg.readPreferenceOrDefault() {
+	case "primary":
+		return readpref.Primary(), nil
+	case "primaryPreferred":
+		return readpref.PrimaryPreferred(), nil
+	case "secondary":
+		return readpref.Secondary(), nil
+	case "secondaryPreferred":
+		return readpref.SecondaryPreferred(), nil
+	case "nearest":
+		return readpref.Nearest(), nil
+	default:
+		return nil, fmt.Errorf("invalid read preference %q", cfg.ReadPreference)
+	}
+}
+
+func (cfg Config) readPreferenceOrDefault() string {
+	if cfg.ReadPreference == "" {
+		return "primary"
+	}
+	return cfg.ReadPreference
+}
+
+func describeWriteConcern(cfg Config, standalone bool) string {
+	w := cfg.WriteConcern
+	if w == "" {
+		w = "majority"
+	}
+	if w == "majority" && standalone {
+		return "1 (downgraded from majority on standalone)"
+	}
+	return w
+}
@@ -0,0 +1,124 @@
+package mongodbx
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func wMarshaled(t *testing.T, wc *writeconcern.WriteConcern) bson.M {
+	t.Helper()
+	typ, data, err := wc.MarshalBSONValue()
+	if err != nil {
+		t.Fatalf("marshal write concern: %v", err)
+	}
+	var doc bson.M
+	if err := bson.UnmarshalValue(typ, data, &doc); err != nil {
+		t.Fatalf("unmarshal write concern: %v", err)
+	}
+	return doc
+}
+
+func TestBuildWriteConcern(t *testing.T) {
+	cases := []struct {
+		name       string
+		cfg        Config
+		standalone bool
+		wantW      interface{}
+		wantErr    bool
+	}{
+		{name: "defaults to majority", cfg: Config{}, wantW: "majority"},
+		{name: "majority downgraded on standalone", cfg: Config{}, standalone: true, wantW: int32(1)},
+		{name: "explicit unacknowledged", cfg: Config{WriteConcern: "0"}, wantW: int32(0)},
+		{name: "explicit node count", cfg: Config{WriteConcern: "3"}, wantW: int32(3)},
+		{name: "rejects non-numeric suffix", cfg: Config{WriteConcern: "3x"}, wantErr: true},
+		{name: "rejects non-positive count", cfg: Config{WriteConcern: "00"}, wantErr: true},
+		{name: "rejects negative count", cfg: Config{WriteConcern: "-1"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wc, err := buildWriteConcern(tc.cfg, tc.standalone)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			doc := wMarshaled(t, wc)
+			if doc["w"] != tc.wantW {
+				t.Fatalf("w = %#v, want %#v", doc["w"], tc.wantW)
+			}
+		})
+	}
+}
+
+func TestBuildWriteConcernJournalAndTimeout(t *testing.T) {
+	wc, err := buildWriteConcern(Config{
+		WriteConcernJournal:  true,
+		WriteConcernWTimeout: 2 * time.Second,
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := wMarshaled(t, wc)
+	if doc["j"] != true {
+		t.Fatalf("j = %#v, want true", doc["j"])
+	}
+	if doc["wtimeout"] != int64(2000) {
+		t.Fatalf("wtimeout = %#v, want 2000", doc["wtimeout"])
+	}
+}
+
+func TestBuildReadPreference(t *testing.T) {
+	cases := []struct {
+		name     string
+		cfg      Config
+		wantMode readpref.Mode
+		wantErr  bool
+	}{
+		{name: "defaults to primary", cfg: Config{}, wantMode: readpref.PrimaryMode},
+		{name: "primary", cfg: Config{ReadPreference: "primary"}, wantMode: readpref.PrimaryMode},
+		{name: "primaryPreferred", cfg: Config{ReadPreference: "primaryPreferred"}, wantMode: readpref.PrimaryPreferredMode},
+		{name: "secondary", cfg: Config{ReadPreference: "secondary"}, wantMode: readpref.SecondaryMode},
+		{name: "secondaryPreferred", cfg: Config{ReadPreference: "secondaryPreferred"}, wantMode: readpref.SecondaryPreferredMode},
+		{name: "nearest", cfg: Config{ReadPreference: "nearest"}, wantMode: readpref.NearestMode},
+		{name: "rejects unknown mode", cfg: Config{ReadPreference: "bogus"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rp, err := buildReadPreference(tc.cfg)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rp.Mode() != tc.wantMode {
+				t.Fatalf("mode = %v, want %v", rp.Mode(), tc.wantMode)
+			}
+		})
+	}
+}
+
+func TestDescribeWriteConcern(t *testing.T) {
+	if got := describeWriteConcern(Config{}, false); got != "majority" {
+		t.Fatalf("got %q, want %q", got, "majority")
+	}
+	if got := describeWriteConcern(Config{}, true); got != "1 (downgraded from majority on standalone)" {
+		t.Fatalf("got %q, want the standalone-downgrade description", got)
+	}
+	if got := describeWriteConcern(Config{WriteConcern: "3"}, false); got != "3" {
+		t.Fatalf("got %q, want %q", got, "3")
+	}
+}
@@ -0,0 +1,50 @@
+package monitors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Monitor is the on-disk shape of a document in the `monitors` collection.
+type Monitor struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Name      string             `bson:"monitor_name"`
+	OwnerID   primitive.ObjectID `bson:"owner_id"`
+	CreatedAt time.Time          `bson:"created_at"`
+	LastSeen  time.Time          `bson:"last_seen"`
+}
+
+// ErrInvalidID is returned when a caller-supplied hex string isn't a valid
+// ObjectID, so callers can distinguish a malformed lookup from a genuine
+// "not found".
+var ErrInvalidID = errors.New("monitors: invalid id")
+
+// ErrNotFound is returned when no monitor matches the given id.
+var ErrNotFound = errors.New("monitors: not found")
+
+// GetByID looks up a monitor by its hex-encoded ObjectID. It returns
+// ErrInvalidID if id isn't a valid ObjectID rather than silently querying
+// with the zero ObjectID, which would match nothing and surface as a
+// confusing "not found".
+func (r *Repository) GetByID(ctx context.Context, id string) (*Monitor, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	var m Monitor
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get monitor %s: %w", id, err)
+	}
+	return &m, nil
+}
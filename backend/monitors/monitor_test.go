@@ -0,0 +1,33 @@
+package monitors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestGetByID_InvalidID(t *testing.T) {
+	repo := &Repository{}
+
+	_, err := repo.GetByID(context.Background(), "not-an-object-id")
+	if !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("GetByID error = %v, want ErrInvalidID", err)
+	}
+}
+
+func TestGetByID_NotFound(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("no matching document", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.monitors", mtest.FirstBatch))
+
+		repo := NewRepository(mt.DB)
+		_, err := repo.GetByID(context.Background(), primitive.NewObjectID().Hex())
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetByID error = %v, want ErrNotFound", err)
+		}
+	})
+}
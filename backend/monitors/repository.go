@@ -0,0 +1,89 @@
+package monitors
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultBatchSize bounds how many documents the driver buffers per network
+// round-trip for ForEach, so a dump of a large collection stays O(1) in
+// memory instead of loading everything at once.
+const defaultBatchSize = 500
+
+// Repository is the data-access layer for the `monitors` collection.
+type Repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository returns a Repository backed by db's `monitors` collection.
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{collection: db.Collection("monitors")}
+}
+
+// ForEach streams every document matching filter to fn, sorted by _id. It
+// sets an explicit BatchSize and disables the server-side cursor timeout so
+// a long-running dump can't be killed mid-iteration, and it stops as soon as
+// fn returns a non-nil error.
+func (r *Repository) ForEach(ctx context.Context, filter bson.M, fn func(Monitor) error) error {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetBatchSize(defaultBatchSize).
+		SetNoCursorTimeout(true).
+		SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("find monitors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var m Monitor
+		if err := cursor.Decode(&m); err != nil {
+			return fmt.Errorf("decode monitor: %w", err)
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Page returns up to limit monitors with _id greater than afterID, sorted by
+// _id. Passing a zero afterID returns the first page. Cursor-based paging on
+// _id is used instead of skip/limit so results stay stable under concurrent
+// inserts.
+func (r *Repository) Page(ctx context.Context, afterID primitive.ObjectID, limit int) ([]Monitor, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("page: limit must be positive, got %d", limit)
+	}
+
+	filter := bson.M{}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find monitors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var page []Monitor
+	if err := cursor.All(ctx, &page); err != nil {
+		return nil, fmt.Errorf("decode monitors: %w", err)
+	}
+	return page, nil
+}
@@ -0,0 +1,86 @@
+package monitors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPage_RejectsNonPositiveLimit(t *testing.T) {
+	repo := &Repository{}
+
+	for _, limit := range []int{0, -1} {
+		if _, err := repo.Page(context.Background(), primitive.NilObjectID, limit); err == nil {
+			t.Fatalf("Page() with limit %d: want an error, got nil", limit)
+		}
+	}
+}
+
+func TestPage_ReturnsDocumentsInOrder(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("page", func(mt *mtest.T) {
+		first := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "monitor_name", Value: "first"}}
+		second := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "monitor_name", Value: "second"}}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.monitors", mtest.FirstBatch, first, second))
+
+		repo := NewRepository(mt.DB)
+		page, err := repo.Page(context.Background(), primitive.NilObjectID, 2)
+		if err != nil {
+			t.Fatalf("Page() error = %v", err)
+		}
+		if len(page) != 2 {
+			t.Fatalf("Page() returned %d monitors, want 2", len(page))
+		}
+		if page[0].Name != "first" || page[1].Name != "second" {
+			t.Fatalf("Page() = %+v, want first then second", page)
+		}
+	})
+}
+
+func TestForEach_VisitsEveryDocumentAndStopsOnError(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("visits every document", func(mt *mtest.T) {
+		first := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "monitor_name", Value: "first"}}
+		second := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "monitor_name", Value: "second"}}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.monitors", mtest.FirstBatch, first, second))
+
+		repo := NewRepository(mt.DB)
+		var seen []string
+		err := repo.ForEach(context.Background(), nil, func(m Monitor) error {
+			seen = append(seen, m.Name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ForEach() error = %v", err)
+		}
+		if len(seen) != 2 || seen[0] != "first" || seen[1] != "second" {
+			t.Fatalf("ForEach() visited %v, want [first second]", seen)
+		}
+	})
+
+	mt.RunOpts("stops on handler error", mtest.NewOptions().ClientType(mtest.Mock), func(mt *mtest.T) {
+		first := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "monitor_name", Value: "first"}}
+		second := bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "monitor_name", Value: "second"}}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.monitors", mtest.FirstBatch, first, second))
+
+		repo := NewRepository(mt.DB)
+		var seen []string
+		wantErr := errors.New("stop here")
+		err := repo.ForEach(context.Background(), nil, func(m Monitor) error {
+			seen = append(seen, m.Name)
+			return wantErr
+		})
+		if err != wantErr {
+			t.Fatalf("ForEach() error = %v, want %v", err, wantErr)
+		}
+		if len(seen) != 1 {
+			t.Fatalf("ForEach() visited %d documents, want it to stop after the first", len(seen))
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package monitors
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// schemaLogger is the subset of *log.Logger EnsureSchema needs, so callers
+// can pass app.App.Logger (or any other *log.Logger) without this package
+// importing internal/app.
+type schemaLogger interface {
+	Printf(format string, v ...interface{})
+}
+
+// EnsureSchemaOptions controls EnsureSchema's behavior.
+type EnsureSchemaOptions struct {
+	// DryRun logs the indexes that would be created without creating them.
+	DryRun bool
+	// Logger receives one line per index describing whether it was
+	// created or already existed. Defaults to a no-op logger.
+	Logger schemaLogger
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(string, ...interface{}) {}
+
+// EnsureSchema idempotently creates the indexes the `monitors` collection
+// needs:
+//   - a unique index on monitor_name
+//   - a TTL index on last_seen, so stale ephemeral monitor state expires on
+//     its own
+//   - a compound index on (owner_id, created_at) for listing a owner's
+//     monitors newest-first
+func EnsureSchema(ctx context.Context, db *mongo.Database, opts EnsureSchemaOptions) error {
+	logger := opts.Logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
+
+	collection := db.Collection("monitors")
+	existing, err := existingIndexNames(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("list existing indexes: %w", err)
+	}
+
+	models := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "monitor_name", Value: 1}},
+			Options: options.Index().SetName("monitor_name_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "last_seen", Value: 1}},
+			Options: options.Index().SetName("last_seen_ttl").SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("owner_id_created_at"),
+		},
+	}
+
+	for _, model := range models {
+		name := *model.Options.Name
+		if existing[name] {
+			logger.Printf("index %s already exists", name)
+			continue
+		}
+
+		if opts.DryRun {
+			logger.Printf("index %s would be created (dry-run)", name)
+			continue
+		}
+
+		if _, err := collection.Indexes().CreateOne(ctx, model); err != nil {
+			return fmt.Errorf("create index %s: %w", name, err)
+		}
+		logger.Printf("index %s created", name)
+	}
+
+	return nil
+}
+
+func existingIndexNames(ctx context.Context, collection *mongo.Collection) (map[string]bool, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	names := map[string]bool{}
+	for cursor.Next(ctx) {
+		var idx struct {
+			Name string `bson:"name"`
+		}
+		if err := cursor.Decode(&idx); err != nil {
+			return nil, err
+		}
+		names[idx.Name] = true
+	}
+	return names, cursor.Err()
+}
@@ -0,0 +1,103 @@
+package monitors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) contains(substr string) bool {
+	for _, line := range l.lines {
+		if strings.Contains(line, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEnsureSchema_SkipsExistingIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("skips what exists, creates the rest", func(mt *mtest.T) {
+		ns := mt.DB.Name() + ".monitors"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "name", Value: "_id_"}},
+				bson.D{{Key: "name", Value: "monitor_name_unique"}},
+			),
+			mtest.CreateSuccessResponse(bson.E{Key: "numIndexesAfter", Value: 3}),
+			mtest.CreateSuccessResponse(bson.E{Key: "numIndexesAfter", Value: 4}),
+		)
+
+		logger := &recordingLogger{}
+		err := EnsureSchema(context.Background(), mt.DB, EnsureSchemaOptions{Logger: logger})
+		if err != nil {
+			t.Fatalf("EnsureSchema() error = %v", err)
+		}
+
+		if !logger.contains("monitor_name_unique already exists") {
+			t.Fatalf("log lines = %v, want a line noting monitor_name_unique already exists", logger.lines)
+		}
+		if !logger.contains("last_seen_ttl created") {
+			t.Fatalf("log lines = %v, want last_seen_ttl to be created", logger.lines)
+		}
+		if !logger.contains("owner_id_created_at created") {
+			t.Fatalf("log lines = %v, want owner_id_created_at to be created", logger.lines)
+		}
+
+		listEvent := mt.GetStartedEvent()
+		if listEvent == nil || listEvent.CommandName != "listIndexes" {
+			t.Fatalf("expected a listIndexes command, got %v", listEvent)
+		}
+		var createCount int
+		for ev := mt.GetStartedEvent(); ev != nil; ev = mt.GetStartedEvent() {
+			if ev.CommandName == "createIndexes" {
+				createCount++
+			}
+		}
+		if createCount != 2 {
+			t.Fatalf("createIndexes issued %d times, want 2 (skipping the one that already exists)", createCount)
+		}
+	})
+}
+
+func TestEnsureSchema_DryRunCreatesNothing(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("dry run", func(mt *mtest.T) {
+		ns := mt.DB.Name() + ".monitors"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "name", Value: "_id_"}}),
+		)
+
+		logger := &recordingLogger{}
+		err := EnsureSchema(context.Background(), mt.DB, EnsureSchemaOptions{DryRun: true, Logger: logger})
+		if err != nil {
+			t.Fatalf("EnsureSchema() error = %v", err)
+		}
+
+		for _, name := range []string{"monitor_name_unique", "last_seen_ttl", "owner_id_created_at"} {
+			if !logger.contains(name + " would be created (dry-run)") {
+				t.Fatalf("log lines = %v, want a dry-run line for %s", logger.lines, name)
+			}
+		}
+
+		for ev := mt.GetStartedEvent(); ev != nil; ev = mt.GetStartedEvent() {
+			if ev.CommandName == "createIndexes" {
+				t.Fatalf("dry-run issued a createIndexes command: %v", ev)
+			}
+		}
+	})
+}
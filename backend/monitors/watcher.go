@@ -0,0 +1,196 @@
+// Package monitors holds the data-access layer for the `monitors`
+// collection: change-stream watching, paginated reads and schema
+// management.
+package monitors
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const resumeTokensCollection = "monitors_resume_tokens"
+
+// Event is a single change-stream notification for the `monitors`
+// collection.
+type Event struct {
+	OperationType string
+	DocumentID    primitive.ObjectID
+	FullDocument  bson.Raw
+	ResumeToken   bson.Raw
+}
+
+// Handler reacts to a single Event. Its resume token is only persisted once
+// Handler returns nil, so a Watcher that crashes mid-handler will redeliver
+// the event on restart rather than silently skip it.
+type Handler func(ctx context.Context, ev Event) error
+
+// Watcher opens a resumable change stream on the `monitors` collection and
+// dispatches events to registered Handlers.
+type Watcher struct {
+	name          string
+	collection    *mongo.Collection
+	resumeTokens  *mongo.Collection
+	operationType string
+	handlers      []Handler
+}
+
+// NewWatcher creates a Watcher identified by name. name is used as the _id
+// of the persisted resume-token document, so distinct watchers on the same
+// database must use distinct names.
+func NewWatcher(db *mongo.Database, name string) *Watcher {
+	return &Watcher{
+		name:         name,
+		collection:   db.Collection("monitors"),
+		resumeTokens: db.Collection(resumeTokensCollection),
+	}
+}
+
+// FilterOperationType restricts the watcher to a single operationType
+// ("insert", "update", "delete", ...). The zero value watches everything.
+func (w *Watcher) FilterOperationType(op string) {
+	w.operationType = op
+}
+
+// OnEvent registers h to be called for every event the watcher observes,
+// in the order registered.
+func (w *Watcher) OnEvent(h Handler) {
+	w.handlers = append(w.handlers, h)
+}
+
+// Run opens the change stream, resuming from the last persisted token if
+// one exists, and blocks dispatching events to the registered handlers
+// until ctx is cancelled or the stream errors.
+func (w *Watcher) Run(ctx context.Context) error {
+	pipeline := mongo.Pipeline{}
+	if w.operationType != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{
+			{Key: "operationType", Value: w.operationType},
+		}}})
+	}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if token, err := w.loadResumeToken(ctx); err != nil {
+		return fmt.Errorf("load resume token: %w", err)
+	} else if token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	stream, err := w.collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("watch monitors: %w", err)
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var raw struct {
+			OperationType string `bson:"operationType"`
+			DocumentKey   struct {
+				ID primitive.ObjectID `bson:"_id"`
+			} `bson:"documentKey"`
+			FullDocument bson.Raw `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&raw); err != nil {
+			return fmt.Errorf("decode change event: %w", err)
+		}
+
+		ev := Event{
+			OperationType: raw.OperationType,
+			DocumentID:    raw.DocumentKey.ID,
+			FullDocument:  raw.FullDocument,
+			ResumeToken:   stream.ResumeToken(),
+		}
+
+		for _, h := range w.handlers {
+			if err := h(ctx, ev); err != nil {
+				return fmt.Errorf("handler: %w", err)
+			}
+		}
+
+		if err := w.saveResumeToken(ctx, ev.ResumeToken); err != nil {
+			return fmt.Errorf("save resume token: %w", err)
+		}
+	}
+
+	return stream.Err()
+}
+
+// Watch opens a dedicated change stream scoped to a single monitor document
+// via a $match on documentKey._id, so the returned channel only delivers
+// events for that document rather than hanging on unrelated updates.
+func (w *Watcher) Watch(ctx context.Context, id string) (<-chan Event, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("parse id %q: %w", id, err)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "documentKey._id", Value: oid},
+		}}},
+	}
+
+	stream, err := w.collection.Watch(ctx, pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		return nil, fmt.Errorf("watch monitor %s: %w", id, err)
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		defer stream.Close(ctx)
+
+		for stream.Next(ctx) {
+			var raw struct {
+				OperationType string   `bson:"operationType"`
+				FullDocument  bson.Raw `bson:"fullDocument"`
+			}
+			if err := stream.Decode(&raw); err != nil {
+				return
+			}
+
+			select {
+			case events <- Event{
+				OperationType: raw.OperationType,
+				DocumentID:    oid,
+				FullDocument:  raw.FullDocument,
+				ResumeToken:   stream.ResumeToken(),
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+type resumeTokenDoc struct {
+	ID    string   `bson:"_id"`
+	Token bson.Raw `bson:"token"`
+}
+
+func (w *Watcher) loadResumeToken(ctx context.Context) (bson.Raw, error) {
+	var doc resumeTokenDoc
+	err := w.resumeTokens.FindOne(ctx, bson.M{"_id": w.name}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.Token, nil
+}
+
+func (w *Watcher) saveResumeToken(ctx context.Context, token bson.Raw) error {
+	_, err := w.resumeTokens.UpdateOne(ctx,
+		bson.M{"_id": w.name},
+		bson.M{"$set": bson.M{"token": token}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
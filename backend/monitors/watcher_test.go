@@ -0,0 +1,145 @@
+package monitors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestResumeToken_SaveThenLoadRoundTrip(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	wantToken, err := bson.Marshal(bson.D{{Key: "_data", Value: "some-resume-token"}})
+	if err != nil {
+		t.Fatalf("marshal token: %v", err)
+	}
+
+	mt.Run("save", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		w := NewWatcher(mt.DB, "watcher-a")
+		if err := w.saveResumeToken(context.Background(), wantToken); err != nil {
+			t.Fatalf("saveResumeToken() error = %v", err)
+		}
+
+		ev := mt.GetStartedEvent()
+		if ev == nil || ev.CommandName != "update" {
+			t.Fatalf("saveResumeToken() sent command %v, want an update command", ev)
+		}
+	})
+
+	mt.Run("load", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.monitors_resume_tokens", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "watcher-a"},
+			{Key: "token", Value: bson.D{{Key: "_data", Value: "some-resume-token"}}},
+		}))
+
+		w := NewWatcher(mt.DB, "watcher-a")
+		token, err := w.loadResumeToken(context.Background())
+		if err != nil {
+			t.Fatalf("loadResumeToken() error = %v", err)
+		}
+		if !bytes.Equal(token, wantToken) {
+			t.Fatalf("loadResumeToken() = %s, want %s", token, wantToken)
+		}
+	})
+}
+
+func TestResumeToken_LoadMissingReturnsNil(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("no token document yet", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.monitors_resume_tokens", mtest.FirstBatch))
+
+		w := NewWatcher(mt.DB, "watcher-a")
+		token, err := w.loadResumeToken(context.Background())
+		if err != nil {
+			t.Fatalf("loadResumeToken() error = %v", err)
+		}
+		if token != nil {
+			t.Fatalf("loadResumeToken() = %v, want nil when no resume-token document exists", token)
+		}
+	})
+}
+
+func changeStreamDoc(resumeToken, op string, docID primitive.ObjectID) bson.D {
+	return bson.D{
+		{Key: "_id", Value: bson.D{{Key: "_data", Value: resumeToken}}},
+		{Key: "operationType", Value: op},
+		{Key: "documentKey", Value: bson.D{{Key: "_id", Value: docID}}},
+		{Key: "fullDocument", Value: bson.D{{Key: "_id", Value: docID}, {Key: "monitor_name", Value: "m"}}},
+	}
+}
+
+func TestWatcherRun_PersistsResumeTokenAfterSuccessfulHandler(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("persists on success", func(mt *mtest.T) {
+		ns := mt.DB.Name() + ".monitors"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".monitors_resume_tokens", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, changeStreamDoc("token-1", "insert", primitive.NewObjectID())),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		w := NewWatcher(mt.DB, "watcher-a")
+		var handled int
+		w.OnEvent(func(ctx context.Context, ev Event) error {
+			handled++
+			return nil
+		})
+
+		if err := w.Run(context.Background()); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if handled != 1 {
+			t.Fatalf("handler called %d times, want 1", handled)
+		}
+
+		ev := mt.GetStartedEvent() // find (loadResumeToken)
+		if ev == nil || ev.CommandName != "find" {
+			t.Fatalf("expected a find command, got %v", ev)
+		}
+		ev = mt.GetStartedEvent() // aggregate (change stream)
+		if ev == nil || ev.CommandName != "aggregate" {
+			t.Fatalf("expected an aggregate command, got %v", ev)
+		}
+		ev = mt.GetStartedEvent() // update (saveResumeToken)
+		if ev == nil || ev.CommandName != "update" {
+			t.Fatalf("expected the resume token to be persisted via an update command, got %v", ev)
+		}
+	})
+}
+
+func TestWatcherRun_HandlerErrorSkipsPersistingResumeToken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("skips persist on handler error", func(mt *mtest.T) {
+		ns := mt.DB.Name() + ".monitors"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".monitors_resume_tokens", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, changeStreamDoc("token-1", "insert", primitive.NewObjectID())),
+		)
+
+		w := NewWatcher(mt.DB, "watcher-a")
+		w.OnEvent(func(ctx context.Context, ev Event) error {
+			return errHandlerFailed
+		})
+
+		err := w.Run(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "handler:") {
+			t.Fatalf("Run() error = %v, want it to wrap the handler error", err)
+		}
+		if strings.Contains(err.Error(), "save resume token") {
+			t.Fatalf("Run() error = %v, want the resume token to never be saved after a handler error", err)
+		}
+	})
+}
+
+var errHandlerFailed = errors.New("handler failed")